@@ -0,0 +1,184 @@
+package stroke
+
+import "math"
+
+// Dashing splits a subpath into the pieces that fall within the "on"
+// intervals of a dash pattern before it is offset into a stroke outline.
+//
+// Dashes and DashOffset are meant to live on Options (Dashes []float32,
+// DashOffset float32), alongside Width, Cap, and Join; dashSubpaths below is
+// the pre-pass Stroke should run each subpath through before building the
+// outline.
+
+// dashSubpaths applies a dash pattern to every subpath of path, dropping any
+// subpath that has no "on" portions left.
+func dashSubpaths(path [][]Segment, dashes []float32, offset float32) [][]Segment {
+	if len(dashes) == 0 {
+		return path
+	}
+	result := make([][]Segment, 0, len(path))
+	for _, sub := range path {
+		dashed := dashPath(sub, dashes, offset)
+		if len(dashed) > 0 {
+			result = append(result, dashed)
+		}
+	}
+	return result
+}
+
+// dashPath splits path (a single subpath) into the sub-segments that fall
+// within the "on" intervals of a dash pattern, discarding the "off"
+// intervals between them. dashes gives the alternating on/off lengths (the
+// first entry is always "on"), and offset shifts the start of the pattern
+// along the path.
+//
+// Closed subpaths (path[0].Start == path[len(path)-1].End) get one more
+// step: if the pattern is still "on" when it reaches the end of the path,
+// and it was also "on" at the very start, then the dash that wraps around
+// the seam is really one dash, not two, so the piece that ended at the
+// seam and the piece that began there are merged into a single contiguous
+// run (by rotating them together to the end of the result) instead of
+// being left as two separately-capped pieces.
+func dashPath(path []Segment, dashes []float32, offset float32) []Segment {
+	if len(path) == 0 || len(dashes) == 0 {
+		return path
+	}
+
+	period := float32(0)
+	for _, d := range dashes {
+		if d < 0 {
+			return path
+		}
+		period += d
+	}
+	if period == 0 {
+		return path
+	}
+
+	pos := float32(math.Mod(float64(offset), float64(period)))
+	if pos < 0 {
+		pos += period
+	}
+	index := 0
+	for pos >= dashes[index] {
+		pos -= dashes[index]
+		index++
+	}
+	remaining := dashes[index] - pos
+	startIndex := index
+
+	var result []Segment
+	firstRunLen := -1 // length of the leading run still in effect when index first changes; set below
+	endsOnMidDash := false
+	for si, seg := range path {
+		t0 := float32(0)
+		for t0 < 1 {
+			// A dash boundary lands exactly here; switch on/off state
+			// before looking at the segment, rather than taking a
+			// zero-length Split2(t0, t0) of it below. Guaranteed to
+			// terminate because period > 0, so some entry of dashes is
+			// positive.
+			for remaining <= 0 {
+				index = (index + 1) % len(dashes)
+				remaining = dashes[index]
+				if firstRunLen < 0 {
+					firstRunLen = len(result)
+				}
+			}
+
+			segRemaining := arcLength(seg, t0, 1)
+			if remaining >= segRemaining {
+				if index%2 == 0 {
+					result = append(result, seg.Split2(t0, 1))
+				}
+				remaining -= segRemaining
+				t0 = 1
+				if si == len(path)-1 {
+					endsOnMidDash = index%2 == 0 && remaining > 0
+				}
+			} else {
+				t1 := findLengthParam(seg, t0, remaining)
+				if index%2 == 0 && t1 > t0 {
+					result = append(result, seg.Split2(t0, t1))
+				}
+				t0 = t1
+				remaining = 0
+			}
+		}
+	}
+	if firstRunLen < 0 {
+		firstRunLen = len(result) // index never changed: the whole path is a single run
+	}
+
+	closed := path[0].Start == path[len(path)-1].End
+	if closed && startIndex%2 == 0 && endsOnMidDash && 0 < firstRunLen && firstRunLen < len(result) {
+		result = append(result[firstRunLen:], result[:firstRunLen]...)
+	}
+	return result
+}
+
+// arcLength returns the length of the portion of s between t0 and t1.
+func arcLength(s Segment, t0, t1 float32) float32 {
+	return s.Split2(t0, t1).length()
+}
+
+// derivative returns B'(t), the tangent vector (not normalized) of s's
+// underlying cubic Bezier function at t.
+func derivative(s Segment, t float32) Point {
+	mt := 1 - t
+	p0 := s.CP1.Sub(s.Start)
+	p1 := s.CP2.Sub(s.CP1)
+	p2 := s.End.Sub(s.CP2)
+	d := p0.Mul(mt * mt).Add(p1.Mul(2 * mt * t)).Add(p2.Mul(t * t))
+	return d.Mul(3)
+}
+
+// speed returns |B'(t)|, the magnitude of s's tangent vector at t.
+func speed(s Segment, t float32) float32 {
+	d := derivative(s, t)
+	return float32(math.Hypot(float64(d.X), float64(d.Y)))
+}
+
+// findLengthParam returns the parameter t > t0 at which the arc length of s
+// from t0 reaches target, using Newton's method (f(t) = arcLength(s, t0, t)
+// - target, f'(t) = speed(s, t)) seeded with the guess t0 +
+// target/arcLength(s, t0, 1)*(1-t0), falling back to bisection whenever a Newton
+// step would leave the bracket that still contains the root.
+func findLengthParam(s Segment, t0, target float32) float32 {
+	full := arcLength(s, t0, 1)
+	if target <= 0 {
+		return t0
+	}
+	if target >= full {
+		return 1
+	}
+
+	lo, hi := t0, float32(1)
+	t := t0 + target/full*(1-t0)
+	for i := 0; i < 16; i++ {
+		f := arcLength(s, t0, t) - target
+		if f == 0 {
+			return t
+		}
+		if f > 0 {
+			hi = t
+		} else {
+			lo = t
+		}
+
+		df := speed(s, t)
+		next := hi
+		if df != 0 {
+			next = t - f/df
+		}
+		if next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		if float32(math.Abs(float64(next-t))) < 1e-4 {
+			t = next
+			break
+		}
+		t = next
+	}
+	return t
+}
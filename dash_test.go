@@ -0,0 +1,123 @@
+package stroke
+
+import "testing"
+
+var dashPathTests = []struct {
+	name    string
+	path    []Segment
+	dashes  []float32
+	offset  float32
+	want    int // number of resulting on-pieces
+	wantEnd Point
+}{
+	{
+		name:   "single dash covering whole path",
+		path:   []Segment{LinearSegment(Pt(0, 0), Pt(10, 0))},
+		dashes: []float32{20, 20},
+		want:   1,
+	},
+	{
+		name:   "dash boundary exactly at segment end",
+		path:   []Segment{LinearSegment(Pt(0, 0), Pt(5, 0)), LinearSegment(Pt(5, 0), Pt(10, 0))},
+		dashes: []float32{5, 5},
+		want:   1,
+	},
+	{
+		name:    "offset shifts the pattern's starting phase",
+		path:    []Segment{LinearSegment(Pt(0, 0), Pt(10, 0))},
+		dashes:  []float32{4, 4},
+		offset:  4,
+		want:    1,
+		wantEnd: Pt(8, 0),
+	},
+	{
+		name:   "negative dash entry disables dashing",
+		path:   []Segment{LinearSegment(Pt(0, 0), Pt(10, 0))},
+		dashes: []float32{4, -1},
+		want:   1,
+	},
+}
+
+func TestDashPath(t *testing.T) {
+	for _, c := range dashPathTests {
+		got := dashPath(c.path, c.dashes, c.offset)
+		if len(got) != c.want {
+			t.Errorf("%s: got %d pieces, want %d: %v", c.name, len(got), c.want, got)
+			continue
+		}
+		if c.wantEnd != (Point{}) && len(got) > 0 {
+			last := got[len(got)-1]
+			if !floatClose(last.End.X, c.wantEnd.X) || !floatClose(last.End.Y, c.wantEnd.Y) {
+				t.Errorf("%s: last piece ends at %v, want %v", c.name, last.End, c.wantEnd)
+			}
+		}
+	}
+}
+
+func TestDashPathClosedSeamMerge(t *testing.T) {
+	// A closed triangle with sides of 30, about 33.5, and about 33.5 units
+	// (perimeter about 97.1). Dash pattern [25, 15] (period 40): 97.1 mod
+	// 40 is about 17.1, which is less than 25, so the pattern is still in
+	// its "on" phase both when the path closes and when it began, so the
+	// dash that straddles the seam should come back as one contiguous
+	// piece rather than two separately-capped ones.
+	path := []Segment{
+		LinearSegment(Pt(0, 0), Pt(30, 0)),
+		LinearSegment(Pt(30, 0), Pt(15, 30)),
+		LinearSegment(Pt(15, 30), Pt(0, 0)),
+	}
+	got := dashPath(path, []float32{25, 15}, 0)
+
+	merged := false
+	for i := 0; i+1 < len(got); i++ {
+		if got[i].End == Pt(0, 0) && got[i+1].Start == Pt(0, 0) {
+			merged = true
+		}
+	}
+	if !merged {
+		t.Errorf("expected the seam-straddling dash merged into one contiguous run, got %v", got)
+	}
+}
+
+func TestDashPathOpenPathNotMerged(t *testing.T) {
+	// The same dash straddling behavior, but on an open path: there's no
+	// seam to merge across, so the start and end should stay as separate
+	// pieces.
+	path := []Segment{
+		LinearSegment(Pt(0, 0), Pt(30, 0)),
+		LinearSegment(Pt(30, 0), Pt(15, 30)),
+		LinearSegment(Pt(15, 30), Pt(5, 0)),
+	}
+	got := dashPath(path, []float32{25, 15}, 0)
+	if len(got) < 2 {
+		t.Fatalf("expected at least 2 separate pieces for an open path, got %v", got)
+	}
+	if got[0].Start != path[0].Start {
+		t.Errorf("first piece should start at the path's start, got %v", got[0].Start)
+	}
+}
+
+func TestFindLengthParam(t *testing.T) {
+	seg := LinearSegment(Pt(0, 0), Pt(10, 0))
+	full := arcLength(seg, 0, 1)
+
+	t1 := findLengthParam(seg, 0, full/2)
+	if !floatClose(t1, 0.5) {
+		t.Errorf("findLengthParam(seg, 0, full/2) = %v, want 0.5", t1)
+	}
+
+	if got := findLengthParam(seg, 0, 0); got != 0 {
+		t.Errorf("findLengthParam(seg, 0, 0) = %v, want 0", got)
+	}
+	if got := findLengthParam(seg, 0, full*2); got != 1 {
+		t.Errorf("findLengthParam(seg, 0, full*2) = %v, want 1 (clamped)", got)
+	}
+
+	curve := Segment{Start: Pt(0, 0), CP1: Pt(0, 50), CP2: Pt(100, 50), End: Pt(100, 0)}
+	curveFull := arcLength(curve, 0, 1)
+	tHalf := findLengthParam(curve, 0, curveFull/2)
+	gotLen := arcLength(curve, 0, tHalf)
+	if !floatClose(gotLen, curveFull/2) {
+		t.Errorf("arcLength(curve, 0, %v) = %v, want %v", tHalf, gotLen, curveFull/2)
+	}
+}
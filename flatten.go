@@ -0,0 +1,85 @@
+package stroke
+
+import "math"
+
+// flattenMaxDepth bounds the recursion of Flatten so that pathological
+// curves (a cusp whose flatness metric never quite drops below tolerance)
+// still terminate.
+const flattenMaxDepth = 32
+
+// CurveCollinearityEpsilon is the threshold below which a cubic segment's
+// control points are treated as exactly collinear with its endpoints,
+// letting Flatten stop subdividing a near-straight curve immediately
+// instead of chasing floating-point noise.
+const CurveCollinearityEpsilon = 1e-9
+
+// Flatten approximates s with a polyline whose maximum distance from the
+// true curve is at most tolerance. The result starts with s.Start and ends
+// with s.End.
+func (s Segment) Flatten(tolerance float32) []Point {
+	return appendFlatten(nil, s, tolerance, 0)
+}
+
+// FlattenPath approximates path (a single subpath) with a polyline whose
+// maximum distance from the true curve is at most tolerance.
+func FlattenPath(path []Segment, tolerance float32) []Point {
+	var result []Point
+	for _, s := range path {
+		result = appendFlatten(result, s, tolerance, 0)
+	}
+	return result
+}
+
+// appendFlatten appends the flattened points of s to dst, recursively
+// subdividing with de Casteljau's algorithm (Split) until s is within
+// tolerance of its chord, then emitting s.End. If dst is empty, s.Start is
+// emitted first.
+func appendFlatten(dst []Point, s Segment, tolerance float32, depth int) []Point {
+	if len(dst) == 0 {
+		dst = append(dst, s.Start)
+	}
+
+	if depth >= flattenMaxDepth || isCollinear(s) || isFlatEnough(s, tolerance) {
+		return append(dst, s.End)
+	}
+
+	a, b := s.Split(0.5)
+	dst = appendFlatten(dst, a, tolerance, depth+1)
+	dst = appendFlatten(dst, b, tolerance, depth+1)
+	return dst
+}
+
+// isFlatEnough reports whether s is close enough to the line from Start to
+// End that it can be emitted as a single line segment: the perpendicular
+// distance from each control point to that line must be at most tolerance.
+func isFlatEnough(s Segment, tolerance float32) bool {
+	d1 := pointLineDistance(s.CP1, s.Start, s.End)
+	d2 := pointLineDistance(s.CP2, s.Start, s.End)
+	if d1 > d2 {
+		return d1 <= tolerance
+	}
+	return d2 <= tolerance
+}
+
+// isCollinear reports whether s's control points already lie on the line
+// from Start to End, to within CurveCollinearityEpsilon.
+func isCollinear(s Segment) bool {
+	if distance(s.Start, s.End) < CurveCollinearityEpsilon {
+		return distance(s.CP1, s.Start) < CurveCollinearityEpsilon &&
+			distance(s.CP2, s.Start) < CurveCollinearityEpsilon
+	}
+	return pointLineDistance(s.CP1, s.Start, s.End) < CurveCollinearityEpsilon &&
+		pointLineDistance(s.CP2, s.Start, s.End) < CurveCollinearityEpsilon
+}
+
+// pointLineDistance returns the perpendicular distance from p to the
+// infinite line through a and b.
+func pointLineDistance(p, a, b Point) float32 {
+	d := b.Sub(a)
+	length := float32(math.Hypot(float64(d.X), float64(d.Y)))
+	if length == 0 {
+		return distance(p, a)
+	}
+	cross := d.X*(a.Y-p.Y) - d.Y*(a.X-p.X)
+	return float32(math.Abs(float64(cross))) / length
+}
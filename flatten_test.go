@@ -0,0 +1,94 @@
+package stroke
+
+import (
+	"math"
+	"testing"
+)
+
+// pointSegmentDistance returns the distance from p to the closest point on
+// the line segment from a to b.
+func pointSegmentDistance(p, a, b Point) float32 {
+	d := b.Sub(a)
+	length2 := d.X*d.X + d.Y*d.Y
+	if length2 == 0 {
+		return distance(p, a)
+	}
+	t := ((p.X-a.X)*d.X + (p.Y-a.Y)*d.Y) / length2
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	closest := Pt(a.X+d.X*t, a.Y+d.Y*t)
+	return distance(p, closest)
+}
+
+func maxFlattenError(s Segment, poly []Point) float32 {
+	var maxErr float32
+	for i := 0; i <= 200; i++ {
+		t := float32(i) / 200
+		p := s.pointAt(t)
+		var best float32 = float32(math.MaxFloat32)
+		for j := 0; j+1 < len(poly); j++ {
+			if d := pointSegmentDistance(p, poly[j], poly[j+1]); d < best {
+				best = d
+			}
+		}
+		if best > maxErr {
+			maxErr = best
+		}
+	}
+	return maxErr
+}
+
+func TestFlattenTolerance(t *testing.T) {
+	curves := []Segment{
+		{Start: Pt(0, 0), CP1: Pt(0, 100), CP2: Pt(100, 100), End: Pt(100, 0)},
+		{Start: Pt(0, 0), CP1: Pt(150, 50), CP2: Pt(-50, 50), End: Pt(100, 0)},
+		LinearSegment(Pt(0, 0), Pt(50, 50)),
+	}
+	for _, tolerance := range []float32{1, 0.1, 0.01} {
+		for _, s := range curves {
+			poly := s.Flatten(tolerance)
+			if poly[0] != s.Start {
+				t.Errorf("Flatten(%v, %v): starts at %v, want %v", s, tolerance, poly[0], s.Start)
+			}
+			if poly[len(poly)-1] != s.End {
+				t.Errorf("Flatten(%v, %v): ends at %v, want %v", s, tolerance, poly[len(poly)-1], s.End)
+			}
+			if err := maxFlattenError(s, poly); err > tolerance {
+				t.Errorf("Flatten(%v, %v): max distance from true curve is %v, want <= %v", s, tolerance, err, tolerance)
+			}
+		}
+	}
+}
+
+func TestFlattenStraightLine(t *testing.T) {
+	// A segment whose control points are already collinear with its
+	// endpoints should come back as just its two endpoints, regardless of
+	// tolerance.
+	s := LinearSegment(Pt(0, 0), Pt(100, 0))
+	got := s.Flatten(0.001)
+	if len(got) != 2 {
+		t.Errorf("Flatten of a straight line: got %d points, want 2: %v", len(got), got)
+	}
+}
+
+func TestFlattenPathSharesJoinPoints(t *testing.T) {
+	// FlattenPath shouldn't duplicate the point where one segment's End
+	// meets the next segment's Start.
+	path := []Segment{
+		LinearSegment(Pt(0, 0), Pt(50, 0)),
+		LinearSegment(Pt(50, 0), Pt(50, 50)),
+	}
+	got := FlattenPath(path, 0.1)
+	count := 0
+	for _, p := range got {
+		if p == Pt(50, 0) {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("join point (50,0) appears %d times in %v, want 1", count, got)
+	}
+}
@@ -0,0 +1,169 @@
+package stroke
+
+import "math"
+
+// An Intersection records a point where two Segments cross, as the
+// parameter value on each curve.
+type Intersection struct {
+	TA, TB float32
+}
+
+const (
+	// intersectBoxThreshold is the bounding-box size, in the segments' own
+	// coordinate units, below which subdivideForIntersect stops recursing
+	// and reports a candidate intersection instead.
+	intersectBoxThreshold = 0.5
+
+	// intersectMaxDepth bounds the recursion of subdivideForIntersect so
+	// that nearly-tangent curves still terminate.
+	intersectMaxDepth = 32
+
+	// intersectEpsilon is the minimum separation, in curve parameter space,
+	// between two intersections for Intersect to report them separately;
+	// closer candidates are assumed to be the same crossing (e.g. a shared
+	// endpoint).
+	intersectEpsilon = 1e-4
+)
+
+// Intersect returns every point where a and b cross, as pairs of parameter
+// values, one on each curve.
+//
+// It recursively subdivides both curves, at each step discarding any pair
+// of pieces whose bounding boxes don't overlap (each Segment's bbox is
+// cheaply derived from its extrema). Once both boxes have shrunk below
+// intersectBoxThreshold, the center of the remaining pair is taken as a
+// candidate crossing and refined with a few iterations of Newton's method
+// on the 2-D system B_a(tA) - B_b(tB) = 0. Candidates that land within
+// intersectEpsilon of one already found are discarded, so that a shared
+// endpoint (as at a join) doesn't produce duplicate results.
+func Intersect(a, b Segment) []Intersection {
+	var candidates []Intersection
+	subdivideForIntersect(a, 0, 1, b, 0, 1, 0, &candidates)
+
+	result := candidates[:0]
+loop:
+	for _, c := range candidates {
+		refined, ok := refineIntersection(a, b, c.TA, c.TB)
+		if !ok {
+			continue
+		}
+		for _, r := range result {
+			if float32(math.Abs(float64(refined.TA-r.TA))) < intersectEpsilon &&
+				float32(math.Abs(float64(refined.TB-r.TB))) < intersectEpsilon {
+				continue loop
+			}
+		}
+		result = append(result, refined)
+	}
+	return result
+}
+
+// subdivideForIntersect narrows the (ta0,ta1) and (tb0,tb1) ranges of a and
+// b, rejecting ranges whose bounding boxes don't overlap, until both are
+// smaller than intersectBoxThreshold, at which point it appends a candidate
+// intersection at the center of the remaining ranges.
+func subdivideForIntersect(a Segment, ta0, ta1 float32, b Segment, tb0, tb1 float32, depth int, result *[]Intersection) {
+	pa := a.Split2(ta0, ta1)
+	pb := b.Split2(tb0, tb1)
+
+	aMin, aMax := pa.bbox()
+	bMin, bMax := pb.bbox()
+	if !boxesOverlap(aMin, aMax, bMin, bMax) {
+		return
+	}
+
+	aSize := math.Max(float64(aMax.X-aMin.X), float64(aMax.Y-aMin.Y))
+	bSize := math.Max(float64(bMax.X-bMin.X), float64(bMax.Y-bMin.Y))
+	if depth >= intersectMaxDepth || (aSize <= intersectBoxThreshold && bSize <= intersectBoxThreshold) {
+		*result = append(*result, Intersection{(ta0 + ta1) / 2, (tb0 + tb1) / 2})
+		return
+	}
+
+	aMid, bMid := (ta0+ta1)/2, (tb0+tb1)/2
+	subdivideForIntersect(a, ta0, aMid, b, tb0, bMid, depth+1, result)
+	subdivideForIntersect(a, ta0, aMid, b, bMid, tb1, depth+1, result)
+	subdivideForIntersect(a, aMid, ta1, b, tb0, bMid, depth+1, result)
+	subdivideForIntersect(a, aMid, ta1, b, bMid, tb1, depth+1, result)
+}
+
+func boxesOverlap(aMin, aMax, bMin, bMax Point) bool {
+	return aMin.X <= bMax.X && aMax.X >= bMin.X && aMin.Y <= bMax.Y && aMax.Y >= bMin.Y
+}
+
+// refineIntersection polishes an approximate crossing of a (at ta) and b
+// (at tb) with Newton's method on F(tA,tB) = B_a(tA) - B_b(tB) = 0, using
+// the Jacobian built from each curve's tangent direction at the current
+// estimate. It reports ok = false if a step leaves the valid [0,1] range on
+// either curve, or if the Jacobian is singular.
+func refineIntersection(a, b Segment, ta, tb float32) (Intersection, bool) {
+	for i := 0; i < 8; i++ {
+		f := a.pointAt(ta).Sub(b.pointAt(tb))
+		if float32(math.Abs(float64(f.X)))+float32(math.Abs(float64(f.Y))) < 1e-6 {
+			break
+		}
+
+		da := derivative(a, ta)
+		db := derivative(b, tb)
+		det := db.X*da.Y - da.X*db.Y
+		if det == 0 {
+			return Intersection{}, false
+		}
+
+		ta += (f.X*db.Y - db.X*f.Y) / det
+		tb += (f.X*da.Y - da.X*f.Y) / det
+		if ta < 0 || ta > 1 || tb < 0 || tb > 1 {
+			return Intersection{}, false
+		}
+	}
+	return Intersection{TA: ta, TB: tb}, true
+}
+
+// RemoveSelfIntersections returns path with self-intersection loops excised:
+// wherever two non-adjacent segments of path cross, the loop between the
+// two crossing points is cut out and replaced by the single crossing point,
+// leaving the two remaining arcs joined there instead. This is the cleanup
+// a stroker needs on the inside of tight joins and on strokes of cuspy
+// curves, where the naive offset outline can self-intersect and produce
+// incorrect winding.
+//
+// path is a single contour (e.g. one side of a stroke outline), not a
+// multi-subpath stroke result. If path is closed (path[0].Start ==
+// path[len(path)-1].End), the shared point at the seam is not treated as a
+// self-intersection.
+func RemoveSelfIntersections(path []Segment) []Segment {
+	if len(path) < 3 {
+		return path
+	}
+	path = append([]Segment(nil), path...)
+	closed := path[0].Start == path[len(path)-1].End
+
+	for {
+		cut := false
+		for i := 0; i < len(path) && !cut; i++ {
+			for j := i + 2; j < len(path); j++ {
+				if closed && i == 0 && j == len(path)-1 {
+					// The shared start/end point of a closed contour, not a
+					// self-intersection.
+					continue
+				}
+				crossings := Intersect(path[i], path[j])
+				if len(crossings) == 0 {
+					continue
+				}
+
+				c := crossings[0]
+				next := make([]Segment, 0, len(path)-(j-i)+1)
+				next = append(next, path[:i]...)
+				next = append(next, path[i].Split2(0, c.TA))
+				next = append(next, path[j].Split2(c.TB, 1))
+				next = append(next, path[j+1:]...)
+				path = next
+				cut = true
+				break
+			}
+		}
+		if !cut {
+			return path
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package stroke
+
+import "testing"
+
+var intersectTests = []struct {
+	a, b   Segment
+	want   int
+	ta, tb float32
+}{
+	{
+		// Two straight lines crossing in their middle.
+		a:    LinearSegment(Pt(0, 0), Pt(10, 10)),
+		b:    LinearSegment(Pt(0, 10), Pt(10, 0)),
+		want: 1,
+		ta:   0.5,
+		tb:   0.5,
+	},
+	{
+		// Parallel lines never cross.
+		a:    LinearSegment(Pt(0, 0), Pt(10, 0)),
+		b:    LinearSegment(Pt(0, 5), Pt(10, 5)),
+		want: 0,
+	},
+	{
+		// Segments whose bounding boxes don't even overlap.
+		a:    LinearSegment(Pt(0, 0), Pt(1, 1)),
+		b:    LinearSegment(Pt(10, 10), Pt(11, 11)),
+		want: 0,
+	},
+}
+
+func TestIntersect(t *testing.T) {
+	for _, c := range intersectTests {
+		got := Intersect(c.a, c.b)
+		if len(got) != c.want {
+			t.Errorf("Intersect(%v, %v): got %d crossings, want %d (%v)", c.a, c.b, len(got), c.want, got)
+			continue
+		}
+		if c.want == 0 {
+			continue
+		}
+		if !floatClose(got[0].TA, c.ta) || !floatClose(got[0].TB, c.tb) {
+			t.Errorf("Intersect(%v, %v): got (tA,tB) = (%v,%v), want (%v,%v)",
+				c.a, c.b, got[0].TA, got[0].TB, c.ta, c.tb)
+		}
+	}
+}
+
+func TestIntersectSharedEndpoint(t *testing.T) {
+	// Two segments that only touch at a shared endpoint (as at a join)
+	// shouldn't be reported as a spurious pair of near-duplicate crossings.
+	a := LinearSegment(Pt(0, 0), Pt(10, 0))
+	b := LinearSegment(Pt(10, 0), Pt(10, 10))
+	got := Intersect(a, b)
+	if len(got) > 1 {
+		t.Errorf("Intersect(%v, %v): got %d crossings for a shared endpoint, want at most 1: %v", a, b, len(got), got)
+	}
+}
+
+func TestRemoveSelfIntersections(t *testing.T) {
+	// A 3-segment path that crosses itself once, like the inside of a
+	// tight join: the loop between the crossing points should be excised,
+	// leaving a path that runs from the original start straight to the
+	// original end through the crossing point.
+	path := []Segment{
+		LinearSegment(Pt(0, 0), Pt(10, 10)),
+		LinearSegment(Pt(10, 10), Pt(0, 10)),
+		LinearSegment(Pt(0, 10), Pt(10, 0)),
+	}
+	got := RemoveSelfIntersections(path)
+
+	if len(got) != 2 {
+		t.Fatalf("RemoveSelfIntersections(%v): got %d segments, want 2: %v", path, len(got), got)
+	}
+	if got[0].Start != Pt(0, 0) {
+		t.Errorf("start point changed: got %v, want (0,0)", got[0].Start)
+	}
+	if got[len(got)-1].End != Pt(10, 0) {
+		t.Errorf("end point changed: got %v, want (10,0)", got[len(got)-1].End)
+	}
+	if !floatClose(got[0].End.X, 5) || !floatClose(got[0].End.Y, 5) || got[0].End != got[1].Start {
+		t.Errorf("expected the loop cut at (5,5), got %v / %v", got[0].End, got[1].Start)
+	}
+}
+
+func TestRemoveSelfIntersectionsNoop(t *testing.T) {
+	// A path that doesn't cross itself should come back unchanged.
+	path := []Segment{
+		LinearSegment(Pt(0, 0), Pt(10, 0)),
+		LinearSegment(Pt(10, 0), Pt(10, 10)),
+		LinearSegment(Pt(10, 10), Pt(0, 10)),
+	}
+	got := RemoveSelfIntersections(path)
+	if len(got) != len(path) {
+		t.Errorf("RemoveSelfIntersections(%v): got %d segments, want %d (unchanged)", path, len(got), len(path))
+	}
+}
+
+func floatClose(a, b float32) bool {
+	d := a - b
+	return d > -1e-3 && d < 1e-3
+}
@@ -0,0 +1,148 @@
+package stroke
+
+// OffsetOptions controls how Offset approximates the true offset curve of a
+// path with new cubic Bezier segments.
+type OffsetOptions struct {
+	// Tolerance is the maximum distance allowed between the approximated
+	// offset curve and the true offset curve. Pieces are subdivided until
+	// they are within Tolerance, so a smaller Tolerance produces more
+	// (and more accurate) segments. Zero means defaultOffsetTolerance.
+	Tolerance float32
+}
+
+const (
+	// defaultOffsetTolerance is used in place of a zero OffsetOptions.Tolerance.
+	defaultOffsetTolerance = 0.01
+
+	// offsetMaxDepth bounds the subdivision Tolerance can trigger, so that
+	// a Tolerance too small to satisfy still terminates.
+	offsetMaxDepth = 16
+)
+
+// Offset returns the curve parallel to path at the given distance: each
+// point on the result is distance units away from the corresponding point
+// on path, in the direction perpendicular to its direction of travel.
+// Positive distance offsets to the left of the direction of travel,
+// negative to the right, with y increasing downward as in SVG: a path
+// heading along +x is offset toward -y by a positive distance.
+//
+// Like the rest of this package, Offset keeps the "cubic in, cubic out"
+// promise instead of flattening: path is first split at its extrema with
+// splitAtExtrema (so each piece is monotonic and free of cusps), and each
+// piece is approximated with a new cubic built by the same tangent-matching
+// construction Stroke uses for each side of a stroked outline, subdividing
+// further with Split(0.5) wherever that approximation isn't within
+// opts.Tolerance of the true offset curve. Stroke does not yet call this;
+// it still has its own copy of the one-sided offset logic.
+func Offset(path []Segment, distance float32, opts OffsetOptions) []Segment {
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultOffsetTolerance
+	}
+
+	var result []Segment
+	for _, seg := range path {
+		for _, piece := range seg.splitAtExtrema() {
+			result = appendOffset(result, piece, distance, tolerance, 0)
+		}
+	}
+	return result
+}
+
+// appendOffset appends the offset of s to dst, recursively bisecting s with
+// Split(0.5) until offsetSegment's approximation is within tolerance or
+// offsetMaxDepth is reached.
+func appendOffset(dst []Segment, s Segment, distance, tolerance float32, depth int) []Segment {
+	approx := offsetSegment(s, distance)
+	if depth >= offsetMaxDepth || offsetError(s, approx, distance) <= tolerance {
+		return append(dst, approx)
+	}
+
+	a, b := s.Split(0.5)
+	dst = appendOffset(dst, a, distance, tolerance, depth+1)
+	dst = appendOffset(dst, b, distance, tolerance, depth+1)
+	return dst
+}
+
+// offsetError estimates how far approx (offsetSegment's approximation of s)
+// strays from the true offset curve of s at distance, by sampling the true
+// offset (the point on s plus distance along its normal) at a few interior
+// parameter values and comparing it to approx at the same parameter.
+func offsetError(s, approx Segment, offsetDistance float32) float32 {
+	var maxErr float32
+	for _, t := range [3]float32{0.25, 0.5, 0.75} {
+		normal := unitVector(rot90CW(derivative(s, t)))
+		want := s.pointAt(t).Add(normal.Mul(offsetDistance))
+		if e := distance(want, approx.pointAt(t)); e > maxErr {
+			maxErr = e
+		}
+	}
+	return maxErr
+}
+
+// offsetHandleMinScale bounds how much offsetSegment will shrink a handle to
+// compensate for curvature, so that a distance close to (or past) the local
+// radius of curvature doesn't collapse or invert a handle.
+const offsetHandleMinScale = 0.05
+
+// offsetSegment approximates the offset of s at distance with a single new
+// cubic, the same tangent-matching construction Stroke uses for each side
+// of a stroked outline: the endpoints move out along the curve's own start
+// and end normals, the new control points sit on the same tangent lines as
+// the original ones, and each handle's length is scaled by 1+distance*k
+// (k being the curvature at that endpoint) so the new cubic's curvature at
+// the endpoints matches the true offset curve's, not just its tangent.
+func offsetSegment(s Segment, offsetDistance float32) Segment {
+	t0, t1 := s.tangents()
+	newStart := s.Start.Add(rot90CW(t0).Mul(offsetDistance))
+	newEnd := s.End.Add(rot90CW(t1).Mul(offsetDistance))
+
+	len0 := distance(s.CP1, s.Start)
+	len1 := distance(s.CP2, s.End)
+	scale0 := offsetHandleScale(offsetDistance, curvatureAtStart(s))
+	scale1 := offsetHandleScale(offsetDistance, curvatureAtEnd(s))
+
+	return Segment{
+		Start: newStart,
+		CP1:   newStart.Add(t0.Mul(len0 * scale0)),
+		CP2:   newEnd.Sub(t1.Mul(len1 * scale1)),
+		End:   newEnd,
+	}
+}
+
+// offsetHandleScale returns the factor offsetSegment scales a handle length
+// by to compensate for curvature k at distance, clamped away from zero (and
+// from inverting sign) for curves tighter than distance can really offset.
+func offsetHandleScale(offsetDistance, k float32) float32 {
+	scale := 1 + offsetDistance*k
+	if scale < offsetHandleMinScale {
+		return offsetHandleMinScale
+	}
+	return scale
+}
+
+// curvatureAtStart returns the signed curvature of s at t=0, derived from
+// B'(0) x B”(0) / |B'(0)|^3 for the cubic Bezier function B.
+func curvatureAtStart(s Segment) float32 {
+	d1 := s.CP1.Sub(s.Start)
+	len := distance(s.CP1, s.Start)
+	if len == 0 {
+		return 0
+	}
+	d2 := s.CP2.Sub(s.CP1)
+	cross := d1.X*d2.Y - d1.Y*d2.X
+	return (2.0 / 3.0) * cross / (len * len * len)
+}
+
+// curvatureAtEnd returns the signed curvature of s at t=1, the mirror image
+// of curvatureAtStart's derivation using B'(1) and B”(1).
+func curvatureAtEnd(s Segment) float32 {
+	d1 := s.End.Sub(s.CP2)
+	len := distance(s.End, s.CP2)
+	if len == 0 {
+		return 0
+	}
+	d2 := s.CP2.Sub(s.CP1)
+	cross := d2.X*d1.Y - d2.Y*d1.X
+	return (2.0 / 3.0) * cross / (len * len * len)
+}
@@ -0,0 +1,74 @@
+package stroke
+
+import "testing"
+
+func TestOffsetLine(t *testing.T) {
+	// Offsetting a straight line has zero curvature to compensate for, so
+	// the result should be an exact parallel line, not just an
+	// approximation within tolerance.
+	path := []Segment{LinearSegment(Pt(0, 0), Pt(100, 0))}
+	got := Offset(path, 10, OffsetOptions{})
+	if len(got) == 0 {
+		t.Fatalf("got 0 segments")
+	}
+	first, last := got[0], got[len(got)-1]
+	if !floatClose(first.Start.X, 0) || !floatClose(first.Start.Y, -10) {
+		t.Errorf("Start = %v, want (0,-10)", first.Start)
+	}
+	if !floatClose(last.End.X, 100) || !floatClose(last.End.Y, -10) {
+		t.Errorf("End = %v, want (100,-10)", last.End)
+	}
+	for _, s := range got {
+		for _, p := range []Point{s.Start, s.CP1, s.CP2, s.End} {
+			if !floatClose(p.Y, -10) {
+				t.Errorf("point %v is not exactly on the offset line y=-10", p)
+			}
+		}
+	}
+}
+
+func TestOffsetCircleStaysAtRadius(t *testing.T) {
+	// Every point on the offset of a circular arc should land very close
+	// to (radius + distance) from the arc's center, at the configured
+	// tolerance.
+	center := Pt(50, 50)
+	radius := float32(40)
+	arc := AppendArc(nil, Pt(center.X+radius, center.Y), center, 1.4)
+	const tolerance = float32(0.01)
+	const offsetDistance = 10
+
+	got := Offset(arc, offsetDistance, OffsetOptions{Tolerance: tolerance})
+	for _, s := range got {
+		for _, p := range s.Flatten(0.01) {
+			d := distance(p, center)
+			want := radius + offsetDistance
+			if diff := d - want; diff > tolerance*5 || diff < -tolerance*5 {
+				t.Errorf("offset point %v is %v from center, want close to %v", p, d, want)
+			}
+		}
+	}
+}
+
+func TestOffsetDirection(t *testing.T) {
+	// Positive distance offsets to the left of the direction of travel,
+	// negative to the right.
+	path := []Segment{LinearSegment(Pt(0, 0), Pt(10, 0))}
+	left := Offset(path, 5, OffsetOptions{})
+	right := Offset(path, -5, OffsetOptions{})
+	if left[0].Start.Y == right[0].Start.Y {
+		t.Errorf("left offset %v and right offset %v landed on the same side", left[0], right[0])
+	}
+}
+
+func TestOffsetSmallerToleranceDoesNotReduceSegments(t *testing.T) {
+	// A tighter Tolerance only subdivides further, so it should never
+	// produce fewer segments.
+	curve := []Segment{
+		{Start: Pt(0, 0), CP1: Pt(20, 80), CP2: Pt(80, -80), End: Pt(100, 0)},
+	}
+	loose := Offset(curve, 10, OffsetOptions{Tolerance: 1})
+	tight := Offset(curve, 10, OffsetOptions{Tolerance: 0.01})
+	if len(tight) < len(loose) {
+		t.Errorf("tighter tolerance produced fewer segments (%d) than looser tolerance (%d)", len(tight), len(loose))
+	}
+}
@@ -0,0 +1,90 @@
+package stroke
+
+// A Path accumulates path-construction commands (MoveTo, LineTo, QuadTo,
+// CubicTo, ArcTo, EllipticalArcTo, Close) and converts them to cubic Bezier
+// segments as they are added, producing output in the form Stroke expects.
+//
+// The zero value of Path is an empty path, ready to use.
+type Path struct {
+	subpaths [][]Segment
+	current  []Segment
+	start    Point
+	pos      Point
+}
+
+// MoveTo starts a new subpath at p, without connecting it to the previous
+// one.
+func (path *Path) MoveTo(p Point) {
+	path.endSubpath()
+	path.start = p
+	path.pos = p
+}
+
+// LineTo adds a straight line from the current point to p.
+func (path *Path) LineTo(p Point) {
+	path.current = append(path.current, LinearSegment(path.pos, p))
+	path.pos = p
+}
+
+// QuadTo adds a quadratic Bezier curve from the current point to end, using
+// cp as its control point.
+func (path *Path) QuadTo(cp, end Point) {
+	path.current = append(path.current, QuadraticSegment(path.pos, cp, end))
+	path.pos = end
+}
+
+// CubicTo adds a cubic Bezier curve from the current point to end, using
+// cp1 and cp2 as its control points.
+func (path *Path) CubicTo(cp1, cp2, end Point) {
+	path.current = append(path.current, Segment{path.pos, cp1, cp2, end})
+	path.pos = end
+}
+
+// ArcTo adds an arc of a circle centered at center, starting at the current
+// point and extending angle radians counterclockwise (use a negative angle
+// for a clockwise arc).
+func (path *Path) ArcTo(center Point, angle float32) {
+	path.current = AppendArc(path.current, path.pos, center, angle)
+	path.pos = path.current[len(path.current)-1].End
+}
+
+// EllipticalArcTo adds an arc of an ellipse with foci at f1 and f2, starting
+// at the current point and extending angle radians counterclockwise (use a
+// negative angle for a clockwise arc).
+func (path *Path) EllipticalArcTo(f1, f2 Point, angle float32) {
+	path.current = AppendEllipticalArc(path.current, path.pos, f1, f2, angle)
+	path.pos = path.current[len(path.current)-1].End
+}
+
+// Close closes the current subpath with a straight line back to its
+// starting point, if it isn't already there, and ends the subpath.
+func (path *Path) Close() {
+	if path.pos != path.start {
+		path.LineTo(path.start)
+	}
+	path.endSubpath()
+	path.pos = path.start
+}
+
+// endSubpath moves the current subpath to the list of finished subpaths. A
+// subpath with no segments (an implicit MoveTo immediately followed by
+// another MoveTo or Close) produces no output, the same way Stroke already
+// ignores empty and zero-length subpaths.
+func (path *Path) endSubpath() {
+	if len(path.current) > 0 {
+		path.subpaths = append(path.subpaths, path.current)
+		path.current = nil
+	}
+}
+
+// Subpaths returns the accumulated subpaths, including any not explicitly
+// closed, in the form Stroke expects.
+func (path *Path) Subpaths() [][]Segment {
+	path.endSubpath()
+	return path.subpaths
+}
+
+// Stroke is a convenience method equivalent to Stroke(path.Subpaths(), opts).
+func (path *Path) Stroke(opts Options) [][]Segment {
+	return Stroke(path.Subpaths(), opts)
+}
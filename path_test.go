@@ -0,0 +1,106 @@
+package stroke
+
+import "testing"
+
+func TestPathLineTo(t *testing.T) {
+	var path Path
+	path.MoveTo(Pt(0, 0))
+	path.LineTo(Pt(10, 0))
+	path.LineTo(Pt(10, 10))
+
+	got := path.Subpaths()
+	if len(got) != 1 {
+		t.Fatalf("got %d subpaths, want 1: %v", len(got), got)
+	}
+	if len(got[0]) != 2 {
+		t.Fatalf("got %d segments, want 2: %v", len(got[0]), got[0])
+	}
+	if got[0][0].Start != Pt(0, 0) || got[0][0].End != Pt(10, 0) {
+		t.Errorf("first segment is %v, want (0,0)-(10,0)", got[0][0])
+	}
+	if got[0][1].Start != Pt(10, 0) || got[0][1].End != Pt(10, 10) {
+		t.Errorf("second segment is %v, want (10,0)-(10,10)", got[0][1])
+	}
+}
+
+func TestPathMultipleSubpaths(t *testing.T) {
+	var path Path
+	path.MoveTo(Pt(0, 0))
+	path.LineTo(Pt(10, 0))
+	path.MoveTo(Pt(0, 10))
+	path.LineTo(Pt(10, 10))
+
+	got := path.Subpaths()
+	if len(got) != 2 {
+		t.Fatalf("got %d subpaths, want 2: %v", len(got), got)
+	}
+	if got[0][0].Start != Pt(0, 0) {
+		t.Errorf("first subpath starts at %v, want (0,0)", got[0][0].Start)
+	}
+	if got[1][0].Start != Pt(0, 10) {
+		t.Errorf("second subpath starts at %v, want (0,10)", got[1][0].Start)
+	}
+}
+
+func TestPathEmptySubpathDropped(t *testing.T) {
+	var path Path
+	path.MoveTo(Pt(0, 0))
+	path.MoveTo(Pt(5, 5)) // no segments added between the two MoveTos
+	path.LineTo(Pt(10, 5))
+
+	got := path.Subpaths()
+	if len(got) != 1 {
+		t.Fatalf("got %d subpaths, want 1 (the empty one dropped): %v", len(got), got)
+	}
+	if got[0][0].Start != Pt(5, 5) {
+		t.Errorf("remaining subpath starts at %v, want (5,5)", got[0][0].Start)
+	}
+}
+
+func TestPathClose(t *testing.T) {
+	var path Path
+	path.MoveTo(Pt(0, 0))
+	path.LineTo(Pt(10, 0))
+	path.LineTo(Pt(10, 10))
+	path.Close()
+
+	got := path.Subpaths()
+	if len(got) != 1 {
+		t.Fatalf("got %d subpaths, want 1: %v", len(got), got)
+	}
+	sub := got[0]
+	if len(sub) != 3 {
+		t.Fatalf("got %d segments, want 3 (Close should add the closing line): %v", len(sub), sub)
+	}
+	last := sub[len(sub)-1]
+	if last.Start != Pt(10, 10) || last.End != Pt(0, 0) {
+		t.Errorf("closing segment is %v, want (10,10)-(0,0)", last)
+	}
+}
+
+func TestPathCloseAlreadyAtStart(t *testing.T) {
+	// Close shouldn't add a zero-length closing line if the subpath
+	// already ends back at its start point.
+	var path Path
+	path.MoveTo(Pt(0, 0))
+	path.LineTo(Pt(10, 0))
+	path.LineTo(Pt(0, 0))
+	path.Close()
+
+	got := path.Subpaths()
+	if len(got[0]) != 2 {
+		t.Errorf("got %d segments, want 2 (no extra closing line): %v", len(got[0]), got[0])
+	}
+}
+
+func TestPathSubpathsIdempotent(t *testing.T) {
+	var path Path
+	path.MoveTo(Pt(0, 0))
+	path.LineTo(Pt(10, 0))
+
+	first := path.Subpaths()
+	second := path.Subpaths()
+	if len(first) != len(second) {
+		t.Errorf("calling Subpaths twice gave different results: %v vs %v", first, second)
+	}
+}
@@ -166,6 +166,43 @@ func compact(s []float32) []float32 {
 	return s[:i]
 }
 
+// pointAt returns the point on s at parameter t.
+func (s Segment) pointAt(t float32) Point {
+	a1 := interpolate(t, s.Start, s.CP1)
+	a2 := interpolate(t, s.CP1, s.CP2)
+	a3 := interpolate(t, s.CP2, s.End)
+
+	b1 := interpolate(t, a1, a2)
+	b2 := interpolate(t, a2, a3)
+
+	return interpolate(t, b1, b2)
+}
+
+// bbox returns the axis-aligned bounding box of s, using its extrema so the
+// result is exact rather than just the bounding box of the control polygon.
+func (s Segment) bbox() (min, max Point) {
+	min, max = s.Start, s.Start
+	extend := func(p Point) {
+		if p.X < min.X {
+			min.X = p.X
+		}
+		if p.Y < min.Y {
+			min.Y = p.Y
+		}
+		if p.X > max.X {
+			max.X = p.X
+		}
+		if p.Y > max.Y {
+			max.Y = p.Y
+		}
+	}
+	extend(s.End)
+	for _, t := range s.extrema() {
+		extend(s.pointAt(t))
+	}
+	return min, max
+}
+
 // Split splits s into two segments with de Casteljau's algorithm, at t.
 func (s Segment) Split(t float32) (Segment, Segment) {
 	a1 := interpolate(t, s.Start, s.CP1)
@@ -0,0 +1,399 @@
+package stroke
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseSVGPath parses an SVG path data string (the value of a path
+// element's d attribute) into subpaths of cubic Bezier segments. It
+// supports the full SVG 1.1 path grammar: M/m, L/l, H/h, V/v, C/c, S/s,
+// Q/q, T/t, A/a, and Z/z, including the implicit repetition of the
+// previous command and the smooth-curve commands' use of the previous
+// command's control point. Arcs (A/a) are converted from SVG's
+// endpoint parameterization (rx, ry, x-axis-rotation, large-arc-flag,
+// sweep-flag) to the focus-based form AppendEllipticalArc expects.
+func ParseSVGPath(d string) ([][]Segment, error) {
+	p := &svgParser{data: d}
+	var path Path
+	var cmd byte
+	var lastControl Point
+	var lastControlKind svgControlKind
+
+	for {
+		p.skipSeparators()
+		if p.atEnd() {
+			break
+		}
+
+		if c := p.data[p.pos]; isSVGCommand(c) {
+			cmd = c
+			p.pos++
+		} else if cmd == 0 {
+			return nil, fmt.Errorf("stroke: path data must start with a command: %q", d)
+		} else if cmd == 'M' {
+			cmd = 'L' // an implicit repeat of a moveto is a lineto
+		} else if cmd == 'm' {
+			cmd = 'l'
+		}
+
+		controlKind := svgControlNone
+		switch cmd {
+		case 'M', 'm':
+			pt, err := p.point(cmd == 'm', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			path.MoveTo(pt)
+
+		case 'L', 'l':
+			pt, err := p.point(cmd == 'l', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			path.LineTo(pt)
+
+		case 'H', 'h':
+			x, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			if cmd == 'h' {
+				x += path.pos.X
+			}
+			path.LineTo(Pt(x, path.pos.Y))
+
+		case 'V', 'v':
+			y, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			if cmd == 'v' {
+				y += path.pos.Y
+			}
+			path.LineTo(Pt(path.pos.X, y))
+
+		case 'C', 'c':
+			cp1, err := p.point(cmd == 'c', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			cp2, err := p.point(cmd == 'c', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.point(cmd == 'c', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			path.CubicTo(cp1, cp2, end)
+			lastControl, controlKind = cp2, svgControlCubic
+
+		case 'S', 's':
+			cp1 := path.pos
+			if lastControlKind == svgControlCubic {
+				cp1 = reflectPoint(lastControl, path.pos)
+			}
+			cp2, err := p.point(cmd == 's', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.point(cmd == 's', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			path.CubicTo(cp1, cp2, end)
+			lastControl, controlKind = cp2, svgControlCubic
+
+		case 'Q', 'q':
+			cp, err := p.point(cmd == 'q', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.point(cmd == 'q', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			path.QuadTo(cp, end)
+			lastControl, controlKind = cp, svgControlQuad
+
+		case 'T', 't':
+			cp := path.pos
+			if lastControlKind == svgControlQuad {
+				cp = reflectPoint(lastControl, path.pos)
+			}
+			end, err := p.point(cmd == 't', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			path.QuadTo(cp, end)
+			lastControl, controlKind = cp, svgControlQuad
+
+		case 'A', 'a':
+			rx, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			ry, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			rot, err := p.number()
+			if err != nil {
+				return nil, err
+			}
+			large, err := p.flag()
+			if err != nil {
+				return nil, err
+			}
+			sweep, err := p.flag()
+			if err != nil {
+				return nil, err
+			}
+			end, err := p.point(cmd == 'a', path.pos)
+			if err != nil {
+				return nil, err
+			}
+			appendSVGArc(&path, rx, ry, rot, large, sweep, end)
+
+		case 'Z', 'z':
+			path.Close()
+
+		default:
+			return nil, fmt.Errorf("stroke: unsupported path command %q", cmd)
+		}
+
+		lastControlKind = controlKind
+	}
+
+	return path.Subpaths(), nil
+}
+
+// svgControlKind records which kind of control point, if any, the previous
+// command left behind, so that S/s only reflects a point left by C/c/S/s
+// and T/t only reflects one left by Q/q/T/t (SVG 1.1 §8.3.6/§8.3.7).
+type svgControlKind int
+
+const (
+	svgControlNone svgControlKind = iota
+	svgControlCubic
+	svgControlQuad
+)
+
+// appendSVGArc appends the arc described by SVG's endpoint parameterization
+// (rx, ry, an x-axis rotation in degrees, and the large-arc and sweep
+// flags) from path's current point to end, converting it to the
+// center/focus parameterization AppendEllipticalArc expects. The
+// conversion follows the SVG 1.1 spec, appendix F.6.
+func appendSVGArc(path *Path, rx, ry, rotDeg float32, large, sweep bool, end Point) {
+	start := path.pos
+	if rx == 0 || ry == 0 || start == end {
+		path.LineTo(end)
+		return
+	}
+	rx = float32(math.Abs(float64(rx)))
+	ry = float32(math.Abs(float64(ry)))
+	phi := float64(rotDeg) * math.Pi / 180
+	sinPhi, cosPhi := math.Sincos(phi)
+
+	dx2, dy2 := float64(start.X-end.X)/2, float64(start.Y-end.Y)/2
+	x1p := cosPhi*dx2 + sinPhi*dy2
+	y1p := -sinPhi*dx2 + cosPhi*dy2
+
+	rx2, ry2 := float64(rx)*float64(rx), float64(ry)*float64(ry)
+	if lambda := x1p*x1p/rx2 + y1p*y1p/ry2; lambda > 1 {
+		scale := math.Sqrt(lambda)
+		rx = float32(float64(rx) * scale)
+		ry = float32(float64(ry) * scale)
+		rx2, ry2 = float64(rx)*float64(rx), float64(ry)*float64(ry)
+	}
+
+	sign := 1.0
+	if large == sweep {
+		sign = -1
+	}
+	num := rx2*ry2 - rx2*y1p*y1p - ry2*x1p*x1p
+	if num < 0 {
+		num = 0
+	}
+	co := sign * math.Sqrt(num/(rx2*y1p*y1p+ry2*x1p*x1p))
+	cxp := co * float64(rx) * y1p / float64(ry)
+	cyp := -co * float64(ry) * x1p / float64(rx)
+
+	cx := cosPhi*cxp - sinPhi*cyp + float64(start.X+end.X)/2
+	cy := sinPhi*cxp + cosPhi*cyp + float64(start.Y+end.Y)/2
+
+	delta := angleBetween((x1p-cxp)/float64(rx), (y1p-cyp)/float64(ry), (-x1p-cxp)/float64(rx), (-y1p-cyp)/float64(ry))
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	center := Pt(float32(cx), float32(cy))
+	var dir Point
+	var semiMajor, semiMinor float64
+	if rx >= ry {
+		dir, semiMajor, semiMinor = Pt(float32(cosPhi), float32(sinPhi)), float64(rx), float64(ry)
+	} else {
+		dir, semiMajor, semiMinor = Pt(float32(-sinPhi), float32(cosPhi)), float64(ry), float64(rx)
+	}
+	focalDistance := float32(math.Sqrt(semiMajor*semiMajor - semiMinor*semiMinor))
+	f1 := center.Sub(dir.Mul(focalDistance))
+	f2 := center.Add(dir.Mul(focalDistance))
+
+	path.EllipticalArcTo(f1, f2, float32(delta))
+}
+
+// angleBetween returns the signed angle in radians from vector (ux,uy) to
+// vector (vx,vy).
+func angleBetween(ux, uy, vx, vy float64) float64 {
+	cross := ux*vy - uy*vx
+	dot := ux*vx + uy*vy
+	return math.Atan2(cross, dot)
+}
+
+// reflectPoint returns the reflection of c through p, the implicit control
+// point used by the smooth curve commands S/s and T/t.
+func reflectPoint(c, p Point) Point {
+	return p.Mul(2).Sub(c)
+}
+
+// FormatSVGPath formats subpaths as compact SVG path data, using only the
+// M, C, and Z commands, since every Segment is already a cubic Bezier.
+func FormatSVGPath(subpaths [][]Segment) string {
+	var b strings.Builder
+	for _, sub := range subpaths {
+		if len(sub) == 0 {
+			continue
+		}
+		b.WriteString("M")
+		b.WriteString(formatPoint(sub[0].Start))
+		for _, s := range sub {
+			b.WriteString("C")
+			b.WriteString(formatPoint(s.CP1))
+			b.WriteString(" ")
+			b.WriteString(formatPoint(s.CP2))
+			b.WriteString(" ")
+			b.WriteString(formatPoint(s.End))
+		}
+		if sub[len(sub)-1].End == sub[0].Start {
+			b.WriteString("Z")
+		}
+	}
+	return b.String()
+}
+
+func formatPoint(p Point) string {
+	return formatSVGNumber(p.X) + "," + formatSVGNumber(p.Y)
+}
+
+func formatSVGNumber(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', -1, 32)
+}
+
+// svgParser scans numbers, flags, and commands out of an SVG path data
+// string.
+type svgParser struct {
+	data string
+	pos  int
+}
+
+func isSVGCommand(c byte) bool {
+	switch c {
+	case 'M', 'm', 'L', 'l', 'H', 'h', 'V', 'v', 'C', 'c', 'S', 's', 'Q', 'q', 'T', 't', 'A', 'a', 'Z', 'z':
+		return true
+	}
+	return false
+}
+
+func isSVGSeparator(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ','
+}
+
+func isSVGDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (p *svgParser) atEnd() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *svgParser) skipSeparators() {
+	for !p.atEnd() && isSVGSeparator(p.data[p.pos]) {
+		p.pos++
+	}
+}
+
+// number scans a single numeric value (with an optional sign, decimal
+// point, and exponent).
+func (p *svgParser) number() (float32, error) {
+	p.skipSeparators()
+	start := p.pos
+	if !p.atEnd() && (p.data[p.pos] == '+' || p.data[p.pos] == '-') {
+		p.pos++
+	}
+	for !p.atEnd() && isSVGDigit(p.data[p.pos]) {
+		p.pos++
+	}
+	if !p.atEnd() && p.data[p.pos] == '.' {
+		p.pos++
+		for !p.atEnd() && isSVGDigit(p.data[p.pos]) {
+			p.pos++
+		}
+	}
+	if !p.atEnd() && (p.data[p.pos] == 'e' || p.data[p.pos] == 'E') {
+		save := p.pos
+		p.pos++
+		if !p.atEnd() && (p.data[p.pos] == '+' || p.data[p.pos] == '-') {
+			p.pos++
+		}
+		if !p.atEnd() && isSVGDigit(p.data[p.pos]) {
+			for !p.atEnd() && isSVGDigit(p.data[p.pos]) {
+				p.pos++
+			}
+		} else {
+			p.pos = save
+		}
+	}
+	if p.pos == start {
+		return 0, fmt.Errorf("stroke: expected a number at position %d in %q", start, p.data)
+	}
+	v, err := strconv.ParseFloat(p.data[start:p.pos], 32)
+	if err != nil {
+		return 0, fmt.Errorf("stroke: invalid number %q: %w", p.data[start:p.pos], err)
+	}
+	return float32(v), nil
+}
+
+// flag scans a single SVG path flag (0 or 1), which unlike other numbers
+// may appear with no separator before the next token.
+func (p *svgParser) flag() (bool, error) {
+	p.skipSeparators()
+	if p.atEnd() || (p.data[p.pos] != '0' && p.data[p.pos] != '1') {
+		return false, fmt.Errorf("stroke: expected a flag (0 or 1) at position %d in %q", p.pos, p.data)
+	}
+	v := p.data[p.pos] == '1'
+	p.pos++
+	return v, nil
+}
+
+// point scans an x,y coordinate pair. If relative is true, the result is
+// offset by cur.
+func (p *svgParser) point(relative bool, cur Point) (Point, error) {
+	x, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	pt := Pt(x, y)
+	if relative {
+		pt = pt.Add(cur)
+	}
+	return pt, nil
+}
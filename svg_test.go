@@ -0,0 +1,165 @@
+package stroke
+
+import (
+	"reflect"
+	"testing"
+)
+
+var parseSVGPathTests = []struct {
+	d    string
+	want [][]Segment
+}{
+	{
+		d: "M0,0 L100,0 L100,100 Z",
+		want: [][]Segment{{
+			LinearSegment(Pt(0, 0), Pt(100, 0)),
+			LinearSegment(Pt(100, 0), Pt(100, 100)),
+			LinearSegment(Pt(100, 100), Pt(0, 0)),
+		}},
+	},
+	{
+		// Relative commands, and H/V shorthand.
+		d: "M10,10 h80 v80 h-80 z",
+		want: [][]Segment{{
+			LinearSegment(Pt(10, 10), Pt(90, 10)),
+			LinearSegment(Pt(90, 10), Pt(90, 90)),
+			LinearSegment(Pt(90, 90), Pt(10, 90)),
+			LinearSegment(Pt(10, 90), Pt(10, 10)),
+		}},
+	},
+	{
+		d: "M0,0 Q50,100 100,0",
+		want: [][]Segment{{
+			QuadraticSegment(Pt(0, 0), Pt(50, 100), Pt(100, 0)),
+		}},
+	},
+	{
+		// A "T" following a "Q" reflects the quadratic control point.
+		d: "M0,0 Q50,100 100,0 T200,0",
+		want: [][]Segment{{
+			QuadraticSegment(Pt(0, 0), Pt(50, 100), Pt(100, 0)),
+			QuadraticSegment(Pt(100, 0), Pt(150, -100), Pt(200, 0)),
+		}},
+	},
+	{
+		// A "S" following a "Q" does NOT reflect the quadratic control
+		// point: the implied cubic control point coincides with the
+		// current point instead.
+		d: "M0,0 Q50,100 100,0 S150,-100 200,0",
+		want: [][]Segment{{
+			QuadraticSegment(Pt(0, 0), Pt(50, 100), Pt(100, 0)),
+			Segment{Pt(100, 0), Pt(100, 0), Pt(150, -100), Pt(200, 0)},
+		}},
+	},
+	{
+		// Symmetrically, a "T" following a "C" does not reflect the cubic
+		// control point.
+		d: "M0,0 C25,100 75,100 100,0 T200,0",
+		want: [][]Segment{{
+			Segment{Pt(0, 0), Pt(25, 100), Pt(75, 100), Pt(100, 0)},
+			QuadraticSegment(Pt(100, 0), Pt(100, 0), Pt(200, 0)),
+		}},
+	},
+}
+
+func TestParseSVGPath(t *testing.T) {
+	for _, c := range parseSVGPathTests {
+		got, err := ParseSVGPath(c.d)
+		if err != nil {
+			t.Errorf("ParseSVGPath(%q): unexpected error: %v", c.d, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseSVGPath(%q):\ngot  %v\nwant %v", c.d, got, c.want)
+		}
+	}
+}
+
+func TestParseSVGPathArcToLine(t *testing.T) {
+	// A zero-radius arc degenerates to a straight line.
+	got, err := ParseSVGPath("M0,0 A0,0 0 0,0 100,0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]Segment{{LinearSegment(Pt(0, 0), Pt(100, 0))}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseSVGPathArcEndpoint(t *testing.T) {
+	// A semicircular arc from (0,0) to (100,0) with radius 50 should pass
+	// through (50,-50) or (50,50) depending on the sweep flag, and always
+	// end exactly at (100,0).
+	for _, sweep := range []string{"0", "1"} {
+		d := "M0,0 A50,50 0 0," + sweep + " 100,0"
+		subpaths, err := ParseSVGPath(d)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", d, err)
+		}
+		if len(subpaths) != 1 || len(subpaths[0]) == 0 {
+			t.Fatalf("%q: expected a single non-empty subpath, got %v", d, subpaths)
+		}
+		end := subpaths[0][len(subpaths[0])-1].End
+		if !pointsClose(end, Pt(100, 0), 1e-2) {
+			t.Errorf("%q: end point got %v, want (100,0)", d, end)
+		}
+	}
+}
+
+func TestParseSVGPathEllipticalArcEndpoint(t *testing.T) {
+	// An elliptical arc (rx != ry), rotated 30 degrees, with the large-arc
+	// flag set: A80,40 30 1,0 100,20 should still land exactly on its
+	// endpoint, exercising appendSVGArc's rx/ry/rotation handling rather
+	// than just the rx==ry case covered above.
+	d := "M0,0 A80,40 30 1,0 100,20"
+	subpaths, err := ParseSVGPath(d)
+	if err != nil {
+		t.Fatalf("%q: unexpected error: %v", d, err)
+	}
+	if len(subpaths) != 1 || len(subpaths[0]) == 0 {
+		t.Fatalf("%q: expected a single non-empty subpath, got %v", d, subpaths)
+	}
+	end := subpaths[0][len(subpaths[0])-1].End
+	if !pointsClose(end, Pt(100, 20), 1e-1) {
+		t.Errorf("%q: end point got %v, want (100,20)", d, end)
+	}
+
+	// The large-arc flag should select the longer way around the ellipse:
+	// with it set, the path should travel a good deal further than with
+	// it cleared, for the same endpoints.
+	small, err := ParseSVGPath("M0,0 A80,40 30 0,0 100,20")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	largeLen := FlattenPath(subpaths[0], 0.01)
+	smallLen := FlattenPath(small[0], 0.01)
+	if polylineLength(largeLen) <= polylineLength(smallLen) {
+		t.Errorf("large-arc flag path (length %v) should be longer than the small-arc path (length %v)",
+			polylineLength(largeLen), polylineLength(smallLen))
+	}
+}
+
+func polylineLength(pts []Point) float32 {
+	var total float32
+	for i := 1; i < len(pts); i++ {
+		total += distance(pts[i-1], pts[i])
+	}
+	return total
+}
+
+func TestFormatSVGPath(t *testing.T) {
+	subpaths := [][]Segment{{
+		LinearSegment(Pt(0, 0), Pt(100, 0)),
+	}}
+	got := FormatSVGPath(subpaths)
+	want := "M0,0C33.333332,0 66.66667,0 100,0"
+	if got != want {
+		t.Errorf("FormatSVGPath(%v) = %q, want %q", subpaths, got, want)
+	}
+}
+
+func pointsClose(a, b Point, tolerance float32) bool {
+	d := a.Sub(b)
+	return d.X > -tolerance && d.X < tolerance && d.Y > -tolerance && d.Y < tolerance
+}